@@ -0,0 +1,140 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+// RedactedValue replaces sensitive values redacted by Sanitizer
+// implementations in this package.
+const RedactedValue = "[PRIVATE DATA HIDDEN]"
+
+// defaultSensitiveFields are the JSON field names JSONSanitizer redacts by
+// default, covering the credential shapes IBM Cloud services and IAM
+// commonly use.
+var defaultSensitiveFields = []string{
+	"apikey",
+	"password",
+	"access_token",
+	"refresh_token",
+	"iam_apikey",
+	"client_secret",
+}
+
+// Sanitizer redacts sensitive data from a dumped HTTP request or response
+// before it is written to the trace logger. contentType is the
+// Content-Type header of the message being sanitized.
+type Sanitizer interface {
+	Sanitize(dump, contentType string) string
+}
+
+// RegexSanitizer is the historical TraceLoggingTransport behavior: it
+// applies trace.Sanitize's fixed set of regular expressions to the full
+// dump, regardless of content type.
+type RegexSanitizer struct{}
+
+// Sanitize implements Sanitizer.
+func (RegexSanitizer) Sanitize(dump, _ string) string {
+	return trace.Sanitize(dump)
+}
+
+// JSONSanitizer redacts the values of configured JSON object keys
+// (Fields), at any nesting depth, in request/response bodies whose
+// Content-Type is application/json. Headers and non-JSON bodies are left
+// untouched. If the body can't be parsed as JSON, it is returned
+// unmodified - callers typically chain a RegexSanitizer first, via
+// MultiSanitizer, to still catch secrets in unparsable bodies.
+type JSONSanitizer struct {
+	// Fields are JSON object keys to redact wherever they appear, e.g.
+	// "apikey" or a service-specific name like "x-api-token".
+	Fields []string
+}
+
+// NewJSONSanitizer creates a JSONSanitizer redacting the given field
+// names in addition to the SDK's default set of credential field names.
+func NewJSONSanitizer(fields ...string) *JSONSanitizer {
+	return &JSONSanitizer{Fields: append(append([]string{}, defaultSensitiveFields...), fields...)}
+}
+
+// Sanitize implements Sanitizer.
+func (s *JSONSanitizer) Sanitize(dump, contentType string) string {
+	if !strings.Contains(contentType, "application/json") {
+		return dump
+	}
+
+	// dump is either a full header+body HTTP dump, or a bare body (e.g.
+	// when called on a captured body for structured trace output).
+	header, body := "", dump
+	if sep := strings.Index(dump, "\r\n\r\n"); sep >= 0 {
+		header, body = dump[:sep+4], dump[sep+4:]
+	}
+	if strings.TrimSpace(body) == "" {
+		return dump
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return dump
+	}
+
+	redacted, err := json.Marshal(s.redact(parsed))
+	if err != nil {
+		return dump
+	}
+
+	return header + string(redacted)
+}
+
+func (s *JSONSanitizer) redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			if s.isSensitiveField(k) {
+				out[k] = RedactedValue
+				continue
+			}
+			out[k] = s.redact(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = s.redact(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (s *JSONSanitizer) isSensitiveField(field string) bool {
+	for _, f := range s.Fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiSanitizer chains Sanitizers, feeding the output of each one into
+// the next.
+type MultiSanitizer []Sanitizer
+
+// Sanitize implements Sanitizer.
+func (m MultiSanitizer) Sanitize(dump, contentType string) string {
+	for _, s := range m {
+		dump = s.Sanitize(dump, contentType)
+	}
+	return dump
+}
+
+// DefaultSanitizer returns the Sanitizer used by TraceLoggingTransport
+// when none is configured: the historical regex-based redaction, followed
+// by JSON-aware redaction of the SDK's default sensitive field names, for
+// backward compatibility with existing trace output.
+func DefaultSanitizer() Sanitizer {
+	return MultiSanitizer{RegexSanitizer{}, NewJSONSanitizer()}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+func TestJSONSanitizerRedactsSensitiveFields(t *testing.T) {
+	s := NewJSONSanitizer()
+	body := `{"apikey":"sekret","username":"bob","nested":{"access_token":"tokvalue"}}`
+
+	got := s.Sanitize(body, "application/json")
+
+	if strings.Contains(got, "sekret") || strings.Contains(got, "tokvalue") {
+		t.Fatalf("expected sensitive values to be redacted, got %q", got)
+	}
+	if !strings.Contains(got, "bob") {
+		t.Fatalf("expected non-sensitive values to survive, got %q", got)
+	}
+	if !strings.Contains(got, RedactedValue) {
+		t.Fatalf("expected redacted fields to contain RedactedValue, got %q", got)
+	}
+}
+
+func TestJSONSanitizerIgnoresNonJSONContentType(t *testing.T) {
+	s := NewJSONSanitizer()
+	body := `{"apikey":"sekret"}`
+
+	got := s.Sanitize(body, "text/plain")
+
+	if got != body {
+		t.Fatalf("expected non-JSON content type to be left untouched, got %q", got)
+	}
+}
+
+func TestJSONSanitizerHandlesFullHTTPDump(t *testing.T) {
+	s := NewJSONSanitizer()
+	dump := "POST /v1/token HTTP/1.1\r\nContent-Type: application/json\r\n\r\n" +
+		`{"apikey":"sekret"}`
+
+	got := s.Sanitize(dump, "application/json")
+
+	if strings.Contains(got, "sekret") {
+		t.Fatalf("expected body to be redacted in full dump, got %q", got)
+	}
+	if !strings.HasPrefix(got, "POST /v1/token HTTP/1.1") {
+		t.Fatalf("expected header portion to be preserved, got %q", got)
+	}
+}
+
+func TestJSONSanitizerCustomFields(t *testing.T) {
+	s := NewJSONSanitizer("client_secret", "x-api-token")
+	body := `{"x-api-token":"sekret","other":"value"}`
+
+	got := s.Sanitize(body, "application/json")
+
+	if strings.Contains(got, "sekret") {
+		t.Fatalf("expected custom field to be redacted, got %q", got)
+	}
+}
+
+func TestRegexSanitizerDelegatesToTraceSanitize(t *testing.T) {
+	s := RegexSanitizer{}
+	// trace.Sanitize is exercised directly elsewhere; here we verify
+	// RegexSanitizer returns exactly what trace.Sanitize returns, and
+	// leaves non-sensitive text unchanged.
+	in := "some ordinary request text"
+	if got := s.Sanitize(in, "text/plain"); got != trace.Sanitize(in) {
+		t.Fatalf("expected RegexSanitizer to return trace.Sanitize's output %q, got %q", trace.Sanitize(in), got)
+	}
+	if got := s.Sanitize(in, "text/plain"); got != in {
+		t.Fatalf("expected non-sensitive text to be unchanged, got %q", got)
+	}
+}
+
+func TestMultiSanitizerChainsInOrder(t *testing.T) {
+	upper := sanitizerFunc(func(dump, _ string) string { return strings.ToUpper(dump) })
+	suffix := sanitizerFunc(func(dump, _ string) string { return dump + "!" })
+
+	got := MultiSanitizer{upper, suffix}.Sanitize("hi", "")
+
+	if got != "HI!" {
+		t.Fatalf("expected chained sanitizers to apply in order, got %q", got)
+	}
+}
+
+func TestDefaultSanitizerRedactsJSONBody(t *testing.T) {
+	got := DefaultSanitizer().Sanitize(`{"apikey":"sekret"}`, "application/json")
+
+	if strings.Contains(got, "sekret") {
+		t.Fatalf("expected DefaultSanitizer to redact apikey, got %q", got)
+	}
+}
+
+// sanitizerFunc adapts a function to a Sanitizer, for composing test
+// fixtures without declaring a named type per case.
+type sanitizerFunc func(dump, contentType string) string
+
+func (f sanitizerFunc) Sanitize(dump, contentType string) string { return f(dump, contentType) }
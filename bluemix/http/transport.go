@@ -1,7 +1,9 @@
 package http
 
 import (
+	"encoding/hex"
 	"net/http"
+	"net/http/httptrace"
 	"net/http/httputil"
 	"strings"
 	"time"
@@ -16,42 +18,100 @@ import (
 // "BLUEMIX_TRACE" environment variable. Sensitive user data will be replaced by
 // text "[PRIVATE DATA HIDDEN]".
 //
+// What gets dumped, and how, is controlled by TraceLoggingOptions - by
+// default, it is read from the IBMCLOUD_HTTP_TRACE_DUMP environment
+// variable so users can opt into headers-only or hex-encoded dumps without
+// code changes.
+//
 // Example:
 //   client := &gohttp.Client{ Transport:
 //       http.NewTraceLoggingTransport(),
 //   }
 //   client.Get("http://www.example.com")
 type TraceLoggingTransport struct {
-	rt http.RoundTripper
+	rt      http.RoundTripper
+	options TraceLoggingOptions
 }
 
 // NewTraceLoggingTransport creates a TraceLoggingTransport wrapping around
 // the passed RoundTripper. If the passed RoundTripper is nil, HTTP
-// DefaultTransport is used.
+// DefaultTransport is used. Dump modes are read from the
+// IBMCLOUD_HTTP_TRACE_DUMP environment variable; use
+// NewTraceLoggingTransportWithOptions to set them programmatically instead.
 func NewTraceLoggingTransport(rt http.RoundTripper) *TraceLoggingTransport {
+	return NewTraceLoggingTransportWithOptions(rt, TraceLoggingOptionsFromEnv())
+}
+
+// NewTraceLoggingTransportWithOptions creates a TraceLoggingTransport
+// wrapping around the passed RoundTripper, using options instead of the
+// IBMCLOUD_HTTP_TRACE_DUMP environment variable. If the passed RoundTripper
+// is nil, HTTP DefaultTransport is used.
+func NewTraceLoggingTransportWithOptions(rt http.RoundTripper, options TraceLoggingOptions) *TraceLoggingTransport {
 	if rt == nil {
-		return &TraceLoggingTransport{
-			rt: http.DefaultTransport,
-		}
+		rt = http.DefaultTransport
+	}
+	if options.Sanitizer == nil {
+		options.Sanitizer = DefaultSanitizer()
 	}
 	return &TraceLoggingTransport{
-		rt: rt,
+		rt:      rt,
+		options: options,
 	}
 }
 
 func (r *TraceLoggingTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
 	start := time.Now()
-	r.dumpRequest(req, start)
+	req, id := tagRequest(req)
+
+	var timing *httpTiming
+	if r.options.DumpTiming {
+		var ct *httptrace.ClientTrace
+		timing, ct = newHTTPTiming(start)
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), ct))
+	}
+
+	var reqBody []byte
+	if r.options.Format != TraceFormatText && r.options.DumpRequest {
+		reqBody = captureBody(&req.Body)
+	}
+
+	r.dumpRequest(req, id, start)
 	resp, err = r.rt.RoundTrip(req)
 	if err != nil {
+		if r.options.Format != TraceFormatText {
+			if r.options.DumpRequest || r.options.DumpResponse {
+				r.logStructured(req, nil, id, start, time.Now(), reqBody, nil, timing, err)
+			}
+		} else if timing != nil {
+			timing.log(id)
+		}
 		return
 	}
-	r.dumpResponse(resp, start)
+	r.dumpResponse(resp, id, start)
+
+	if r.options.Format != TraceFormatText {
+		var resBody []byte
+		if r.options.DumpResponse {
+			resBody = captureBody(&resp.Body)
+		}
+		if r.options.DumpRequest || r.options.DumpResponse {
+			r.logStructured(req, resp, id, start, time.Now(), reqBody, resBody, timing, nil)
+		}
+	} else {
+		logServerRequestID(id, resp)
+		if timing != nil {
+			timing.log(id)
+		}
+	}
 	return
 }
 
-func (r *TraceLoggingTransport) dumpRequest(req *http.Request, start time.Time) {
-	shouldDisplayBody := !strings.Contains(req.Header.Get("Content-Type"), "multipart/form-data")
+func (r *TraceLoggingTransport) dumpRequest(req *http.Request, id string, start time.Time) {
+	if r.options.Format != TraceFormatText || !r.options.DumpRequest {
+		return
+	}
+
+	shouldDisplayBody := r.options.DumpBody && !strings.Contains(req.Header.Get("Content-Type"), "multipart/form-data")
 
 	dumpedRequest, err := httputil.DumpRequest(req, shouldDisplayBody)
 	if err != nil {
@@ -59,29 +119,48 @@ func (r *TraceLoggingTransport) dumpRequest(req *http.Request, start time.Time)
 		return
 	}
 
-	trace.Logger.Printf("\n%s [%s]\n%s\n",
+	trace.Logger.Printf("\n%s [%s] [%s]\n%s\n",
 		terminal.HeaderColor(T("REQUEST:")),
 		start.Format(time.RFC3339),
-		trace.Sanitize(string(dumpedRequest)))
+		id,
+		r.sanitizeDump(dumpedRequest, req.Header.Get("Content-Type"), r.options.DumpRequestHex))
 
-	if !shouldDisplayBody {
+	if r.options.DumpBody && !shouldDisplayBody {
 		trace.Logger.Println("[MULTIPART/FORM-DATA CONTENT HIDDEN]")
 	}
 }
 
-func (r *TraceLoggingTransport) dumpResponse(res *http.Response, start time.Time) {
+func (r *TraceLoggingTransport) dumpResponse(res *http.Response, id string, start time.Time) {
+	if r.options.Format != TraceFormatText || !r.options.DumpResponse {
+		return
+	}
+
 	end := time.Now()
 
-	dumpedResponse, err := httputil.DumpResponse(res, true)
+	dumpedResponse, err := httputil.DumpResponse(res, r.options.DumpBody)
 	if err != nil {
 		trace.Logger.Printf(T("An error occurred while dumping response:\n{{.Error}}\n", map[string]interface{}{"Error": err.Error()}))
 		return
 	}
 
-	trace.Logger.Printf("\n%s [%s] %s %.0fms\n%s\n",
+	trace.Logger.Printf("\n%s [%s] [%s] %s %.0fms\n%s\n",
 		terminal.HeaderColor(T("RESPONSE:")),
 		end.Format(time.RFC3339),
+		id,
 		terminal.HeaderColor(T("Elapsed:")),
 		end.Sub(start).Seconds()*1000,
-		trace.Sanitize(string(dumpedResponse)))
+		r.sanitizeDump(dumpedResponse, res.Header.Get("Content-Type"), r.options.DumpResponseHex))
+}
+
+// sanitizeDump always redacts sensitive fields via r.options.Sanitizer
+// first; when asHex is set, it then hex-encodes the sanitized text so
+// binary bodies don't corrupt trace output. Sanitizing before hex-encoding
+// is required - otherwise secrets in headers/bodies would just be hidden
+// behind a trivially reversible encoding instead of actually redacted.
+func (r *TraceLoggingTransport) sanitizeDump(dumped []byte, contentType string, asHex bool) string {
+	sanitized := r.options.Sanitizer.Sanitize(string(dumped), contentType)
+	if asHex {
+		return hex.EncodeToString([]byte(sanitized))
+	}
+	return sanitized
 }
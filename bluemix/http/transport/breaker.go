@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a RoundTripper wrapped with CircuitBreaker
+// while the breaker is open, instead of dispatching the request.
+var ErrCircuitOpen = errors.New("transport: circuit breaker open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreakerOptions configures the CircuitBreaker Link.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures (network
+	// errors or 5xx responses) that opens the breaker.
+	FailureThreshold int
+
+	// Cooldown is how long the breaker stays open before allowing one
+	// half-open probe request through.
+	Cooldown time.Duration
+}
+
+// DefaultCircuitBreakerOptions opens the breaker after 5 consecutive
+// failures and probes again after 30s.
+func DefaultCircuitBreakerOptions() CircuitBreakerOptions {
+	return CircuitBreakerOptions{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// CircuitBreaker returns a Link that stops dispatching requests, returning
+// ErrCircuitOpen instead, once FailureThreshold consecutive failures are
+// observed on the wrapped RoundTripper. After Cooldown it lets a single
+// request through as a probe: success closes the breaker, failure reopens
+// it for another Cooldown period.
+func CircuitBreaker(opts CircuitBreakerOptions) Link {
+	return func(next http.RoundTripper) http.RoundTripper {
+		b := &breaker{opts: opts}
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !b.allow() {
+				return nil, ErrCircuitOpen
+			}
+
+			resp, err := next.RoundTrip(req)
+			b.record(err == nil && resp.StatusCode < 500)
+			return resp, err
+		})
+	}
+}
+
+type breaker struct {
+	opts CircuitBreakerOptions
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow reports whether the caller may dispatch its request. It also
+// performs the open->half-open transition: the single caller that
+// observes the cooldown having elapsed flips the state to breakerHalfOpen
+// and is let through as the probe. Because allow runs under b.mu, that
+// transition and the "am I the probe" check happen atomically, so every
+// other concurrent caller - whether it still sees breakerOpen or arrives
+// just after and sees breakerHalfOpen - is rejected until record()
+// resolves the probe.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.opts.Cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.opts.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
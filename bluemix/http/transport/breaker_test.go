@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	opts := CircuitBreakerOptions{FailureThreshold: 2, Cooldown: time.Hour}
+	failing := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("boom")
+	})
+	rt := CircuitBreaker(opts)(failing)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	for i := 0; i < opts.FailureThreshold; i++ {
+		if _, err := rt.RoundTrip(req); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("attempt %d: expected underlying failure, got %v", i, err)
+		}
+	}
+
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker to be open after %d consecutive failures, got %v", opts.FailureThreshold, err)
+	}
+}
+
+// TestCircuitBreakerHalfOpenAllowsOnlyOneProbe reproduces the flood
+// scenario a circuit breaker exists to prevent: many concurrent callers
+// arriving right after cooldown against a slow, still-struggling
+// downstream must not all be let through - only one probe request should
+// be dispatched while the breaker is half-open.
+func TestCircuitBreakerHalfOpenAllowsOnlyOneProbe(t *testing.T) {
+	opts := CircuitBreakerOptions{FailureThreshold: 1, Cooldown: 10 * time.Millisecond}
+
+	var dispatched int32
+	slowFailing := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		atomic.AddInt32(&dispatched, 1)
+		time.Sleep(20 * time.Millisecond)
+		return nil, errors.New("still down")
+	})
+	rt := CircuitBreaker(opts)(slowFailing)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	// Trip the breaker.
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatalf("expected initial request to fail")
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker to be open, got %v", err)
+	}
+
+	time.Sleep(opts.Cooldown + 5*time.Millisecond)
+
+	const concurrent = 20
+	var wg sync.WaitGroup
+	var rejected int32
+	wg.Add(concurrent)
+	for i := 0; i < concurrent; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := rt.RoundTrip(req); errors.Is(err, ErrCircuitOpen) {
+				atomic.AddInt32(&rejected, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&dispatched); got != 2 {
+		t.Fatalf("expected exactly 2 requests dispatched to the downstream (initial trip + single probe), got %d", got)
+	}
+	if got := atomic.LoadInt32(&rejected); got != concurrent-1 {
+		t.Fatalf("expected %d of %d concurrent callers to be rejected with ErrCircuitOpen, got %d", concurrent-1, concurrent, got)
+	}
+}
+
+func TestCircuitBreakerClosesAfterSuccessfulProbe(t *testing.T) {
+	opts := CircuitBreakerOptions{FailureThreshold: 1, Cooldown: 10 * time.Millisecond}
+
+	var shouldFail int32 = 1
+	rt := CircuitBreaker(opts)(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		if atomic.LoadInt32(&shouldFail) == 1 {
+			return nil, errors.New("down")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatalf("expected initial request to fail")
+	}
+
+	atomic.StoreInt32(&shouldFail, 0)
+	time.Sleep(opts.Cooldown + 5*time.Millisecond)
+
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("expected probe request to succeed, got %v", err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("expected breaker to be closed after successful probe, got %v", err)
+	}
+}
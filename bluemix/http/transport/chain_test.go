@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestChainUsesLinksInOrderOutermostFirst(t *testing.T) {
+	var order []string
+	link := func(name string) Link {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	final := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		order = append(order, "final")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	rt := NewChain().Use(link("outer")).Use(link("inner")).Final(final)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"outer", "inner", "final"}
+	if len(order) != len(want) {
+		t.Fatalf("expected call order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected call order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestChainFinalDefaultsToDefaultTransport(t *testing.T) {
+	rt := NewChain().Final(nil)
+
+	if rt != http.DefaultTransport {
+		t.Fatalf("expected an empty chain terminated with nil to return http.DefaultTransport")
+	}
+}
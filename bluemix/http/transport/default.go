@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"net/http"
+
+	ibmhttp "github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/http"
+)
+
+// NewDefaultTransportChain returns a RoundTripper suitable as the
+// Transport of an http.Client used by bluemix/rest clients and CLI
+// plugins: retry with backoff on 5xx/network errors, a circuit breaker to
+// stop hammering a downed service, and TraceLoggingTransport for request
+// tracing (which already tags every request with a correlation ID - see
+// ibmhttp.RequestIDContextKey). final is the terminal RoundTripper
+// (http.DefaultTransport if nil).
+//
+// Plugin authors who need more control - e.g. span propagation via Span,
+// or different retry/breaker thresholds - should build their own Chain
+// instead of using this constructor.
+func NewDefaultTransportChain(final http.RoundTripper) http.RoundTripper {
+	return NewChain().
+		Use(CircuitBreaker(DefaultCircuitBreakerOptions())).
+		Use(Retry(DefaultRetryOptions())).
+		Use(tracing).
+		Final(final)
+}
+
+// tracing is the Link form of ibmhttp.NewTraceLoggingTransport, used by
+// NewDefaultTransportChain.
+func tracing(next http.RoundTripper) http.RoundTripper {
+	return ibmhttp.NewTraceLoggingTransport(next)
+}
@@ -0,0 +1,144 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// errCannotRewindBody is returned by rewindBody when a request has a body
+// but no GetBody to re-buffer it from - the previous attempt already
+// drained req.Body, so resending it now would silently ship a truncated
+// or empty body instead of the original payload.
+var errCannotRewindBody = errors.New("transport: request body can't be rewound for retry (no GetBody)")
+
+// idempotentMethods are the HTTP methods Retry retries on a network error
+// (as opposed to a 5xx status, which is retried regardless of method)
+// without the caller opting in via RetryOptions.RetryNonIdempotent.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// RetryOptions configures the Retry Link.
+type RetryOptions struct {
+	// MaxRetries is the maximum number of additional attempts after the
+	// first. Zero disables retrying.
+	MaxRetries int
+
+	// BaseDelay bounds the backoff before the first retry; each subsequent
+	// retry doubles the bound, up to MaxDelay. The actual delay is drawn at
+	// random between zero and the bound (full jitter).
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed backoff, before Retry-After overrides it.
+	MaxDelay time.Duration
+
+	// RetryNonIdempotent allows retrying methods other than GET, HEAD,
+	// OPTIONS, PUT, and DELETE after a network error. POST and PATCH are
+	// only retried on an HTTP 5xx response, where no request was
+	// necessarily applied twice server-side.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryOptions returns conservative retry settings: 3 retries,
+// starting at 500ms and capped at 30s of backoff.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// Retry returns a Link that retries requests on a 5xx response or a
+// network error, using exponential backoff with jitter. It honors a
+// Retry-After header given in seconds on a retried (5xx) response; 429
+// responses are not retried. It re-buffers the request body
+// via req.GetBody so each attempt sends an unconsumed body.
+func Retry(opts RetryOptions) Link {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var resp *http.Response
+			var err error
+
+			for attempt := 0; ; attempt++ {
+				resp, err = next.RoundTrip(req)
+				if attempt >= opts.MaxRetries || !shouldRetry(req, resp, err, opts) {
+					return resp, err
+				}
+				if rerr := rewindBody(req); rerr != nil {
+					return resp, err
+				}
+
+				delay := retryDelay(opts, attempt, resp)
+				if resp != nil {
+					drainAndClose(resp.Body)
+				}
+				time.Sleep(delay)
+			}
+		})
+	}
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error, opts RetryOptions) bool {
+	if err != nil {
+		return opts.RetryNonIdempotent || idempotentMethods[req.Method]
+	}
+	return resp != nil && resp.StatusCode >= 500
+}
+
+// rewindBody re-buffers the request body for a retry via req.GetBody, the
+// same mechanism net/http uses to replay a request across redirects. A
+// request with no body has nothing to rewind. A request that does have a
+// body but no GetBody (e.g. built around a hand-rolled io.Reader that
+// http.NewRequest couldn't snapshot) can't be safely retried, since its
+// body was already drained by the prior attempt - rewindBody returns
+// errCannotRewindBody so the caller aborts the retry loop instead of
+// resending a truncated body unnoticed.
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody == nil {
+		return errCannotRewindBody
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// retryDelay computes the next backoff: Retry-After if the response sent
+// one, else exponential backoff with full jitter, capped at MaxDelay.
+func retryDelay(opts RetryOptions, attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+
+	backoff := opts.BaseDelay << attempt
+	if backoff > opts.MaxDelay {
+		backoff = opts.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// drainAndClose discards body and closes it, which allows the underlying
+// connection to be reused instead of closed when a retried response body
+// won't be read further by the caller.
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, 4096))
+	body.Close()
+}
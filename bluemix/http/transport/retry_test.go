@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryRetriesOnServerError(t *testing.T) {
+	var attempts int32
+	rt := Retry(RetryOptions{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})(
+		roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			if attempts < 3 {
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+	)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts (1 + 2 retries), got %d", attempts)
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	rt := Retry(RetryOptions{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})(
+		roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		}),
+	)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected final 503 to be returned, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts (1 + 2 retries), got %d", attempts)
+	}
+}
+
+func TestRetryDoesNotRetryNonIdempotentMethodOnNetworkError(t *testing.T) {
+	var attempts int32
+	boom := errors.New("boom")
+	rt := Retry(DefaultRetryOptions())(
+		roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			return nil, boom
+		}),
+	)
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+
+	if _, err := rt.RoundTrip(req); !errors.Is(err, boom) {
+		t.Fatalf("expected boom to be returned, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected POST network error not to be retried, got %d attempts", attempts)
+	}
+}
+
+// TestRetryAbortsWhenBodyCannotBeRewound reproduces the bug a retry loop
+// must not have: a request with a body but no GetBody has already been
+// drained by the first attempt, so resending it would silently ship an
+// empty body. The retry loop must give up instead of resending it
+// unnoticed.
+func TestRetryAbortsWhenBodyCannotBeRewound(t *testing.T) {
+	var attempts int32
+	rt := Retry(RetryOptions{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})(
+		roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			io.ReadAll(req.Body)
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		}),
+	)
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("payload"))
+	req.GetBody = nil
+
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected retry loop to abort after the first attempt when the body can't be rewound, got %d attempts", attempts)
+	}
+}
+
+func TestRewindBodyRestoresBodyFromGetBody(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("payload"))
+	io.ReadAll(req.Body)
+
+	if err := rewindBody(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := io.ReadAll(req.Body)
+	if !bytes.Equal(got, []byte("payload")) {
+		t.Fatalf("expected rewound body to read %q, got %q", "payload", got)
+	}
+}
+
+func TestRewindBodyErrorsWithoutGetBody(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("payload"))
+	req.GetBody = nil
+
+	if err := rewindBody(req); !errors.Is(err, errCannotRewindBody) {
+		t.Fatalf("expected errCannotRewindBody, got %v", err)
+	}
+}
+
+func TestRewindBodyNoopForEmptyBody(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	if err := rewindBody(req); err != nil {
+		t.Fatalf("expected nil error for a request with no body, got %v", err)
+	}
+}
+
+func TestRetryDelayHonorsRetryAfterHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+
+	got := retryDelay(RetryOptions{BaseDelay: time.Millisecond, MaxDelay: time.Hour}, 0, resp)
+
+	if got != 2*time.Second {
+		t.Fatalf("expected Retry-After to override backoff with 2s, got %s", got)
+	}
+}
+
+func TestRetryDelayCapsAtMaxDelay(t *testing.T) {
+	opts := RetryOptions{BaseDelay: time.Hour, MaxDelay: time.Millisecond}
+
+	got := retryDelay(opts, 5, nil)
+
+	if got > opts.MaxDelay {
+		t.Fatalf("expected backoff to be capped at %s, got %s", opts.MaxDelay, got)
+	}
+}
@@ -0,0 +1,11 @@
+package transport
+
+import "net/http"
+
+// roundTripperFunc adapts a function to an http.RoundTripper, the same way
+// http.HandlerFunc adapts a function to an http.Handler.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
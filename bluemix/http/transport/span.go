@@ -0,0 +1,27 @@
+package transport
+
+import "net/http"
+
+// SpanTracer starts a span around a RoundTrip and injects its propagation
+// headers (e.g. traceparent, uber-trace-id) into the outgoing request.
+// Implement this against whatever tracing SDK (OpenTelemetry, Jaeger, ...)
+// the embedding CLI plugin already uses; the transport package has no
+// tracing SDK dependency of its own.
+type SpanTracer interface {
+	// StartSpan returns req with propagation headers injected, and an end
+	// function to call once the response (or error) is available.
+	StartSpan(req *http.Request) (out *http.Request, end func(resp *http.Response, err error))
+}
+
+// Span returns a Link that wraps each RoundTrip in a span started and
+// ended via tracer.
+func Span(tracer SpanTracer) Link {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req, end := tracer.StartSpan(req)
+			resp, err := next.RoundTrip(req)
+			end(resp, err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+type contextKey string
+
+// RequestIDContextKey is the context key under which TraceLoggingTransport
+// stores the client-generated transaction ID for a request. Callers can
+// read it back from a response's request context
+// (resp.Request.Context().Value(http.RequestIDContextKey)) - or more
+// conveniently via RequestIDFromResponse - to correlate their own error
+// reports with the HTTP trace log.
+const RequestIDContextKey contextKey = "ibmcloud-http-trace-request-id"
+
+// requestIDHeader is the header TraceLoggingTransport sets on outgoing
+// requests, unless the caller already set one.
+const requestIDHeader = "X-Request-ID"
+
+// serverRequestIDHeaders are response headers IBM Cloud services use to
+// report their own request/transaction ID back to the client.
+var serverRequestIDHeaders = []string{"X-Request-ID", "X-Global-Transaction-Id"}
+
+// RequestIDFromResponse returns the client-side transaction ID
+// TraceLoggingTransport generated for the request that produced resp, if
+// any. It is false if resp is nil or the request wasn't routed through a
+// TraceLoggingTransport.
+func RequestIDFromResponse(resp *http.Response) (string, bool) {
+	if resp == nil || resp.Request == nil {
+		return "", false
+	}
+	id, ok := resp.Request.Context().Value(RequestIDContextKey).(string)
+	return id, ok
+}
+
+// newRequestID returns a random RFC 4122 version 4 UUID used to correlate
+// the REQUEST/RESPONSE/timing log lines of a single RoundTrip, modeled
+// after terraform-plugin-sdk's tf_http_trans_id.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// tagRequest assigns req a transaction ID, injecting it as
+// requestIDHeader unless the caller already set one, and stashes it on the
+// request's context under RequestIDContextKey. It returns a clone of req
+// carrying the change and the ID in effect - per the http.RoundTripper
+// contract, RoundTrip must not modify the request it's given, so the
+// caller's *http.Request and its Header map are left untouched.
+func tagRequest(req *http.Request) (*http.Request, string) {
+	id := req.Header.Get(requestIDHeader)
+	if id == "" {
+		id = newRequestID()
+	}
+
+	cloned := req.Clone(context.WithValue(req.Context(), RequestIDContextKey, id))
+	cloned.Header.Set(requestIDHeader, id)
+	return cloned, id
+}
+
+// logServerRequestID logs any server-assigned transaction ID found on
+// resp, alongside the client-side id, so the two can be cross-referenced
+// when filing support tickets against IBM Cloud services. It is only used
+// in text mode - structured modes carry the same information via
+// TraceRecord.ServerRequestID so the output stays one record per line.
+func logServerRequestID(id string, resp *http.Response) {
+	for h, v := range serverRequestIDs(resp) {
+		trace.Logger.Printf("[%s] %s: %s\n", id, h, v)
+	}
+}
+
+// serverRequestIDs collects any server-assigned transaction ID headers
+// present on resp.
+func serverRequestIDs(resp *http.Response) map[string]string {
+	ids := map[string]string{}
+	for _, h := range serverRequestIDHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			ids[h] = v
+		}
+	}
+	return ids
+}
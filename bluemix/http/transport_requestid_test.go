@@ -0,0 +1,92 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewRequestIDLooksLikeUUIDv4(t *testing.T) {
+	id := newRequestID()
+
+	if !uuidV4Pattern.MatchString(id) {
+		t.Fatalf("expected a v4 UUID, got %q", id)
+	}
+}
+
+func TestTagRequestGeneratesIDWhenHeaderAbsent(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	tagged, id := tagRequest(req)
+
+	if id == "" {
+		t.Fatalf("expected a non-empty request id")
+	}
+	if got := tagged.Header.Get(requestIDHeader); got != id {
+		t.Fatalf("expected tagged request header %q to be %q, got %q", requestIDHeader, id, got)
+	}
+}
+
+func TestTagRequestKeepsCallerSuppliedID(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	req.Header.Set(requestIDHeader, "caller-id")
+
+	_, id := tagRequest(req)
+
+	if id != "caller-id" {
+		t.Fatalf("expected caller-supplied request id to be preserved, got %q", id)
+	}
+}
+
+// TestTagRequestDoesNotMutateCallersRequest guards the http.RoundTripper
+// contract - RoundTrip must not modify the request it's given. tagRequest
+// must hand back a clone rather than setting the header on req itself.
+func TestTagRequestDoesNotMutateCallersRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	tagged, _ := tagRequest(req)
+
+	if req.Header.Get(requestIDHeader) != "" {
+		t.Fatalf("expected caller's request header to be left untouched, got %q", req.Header.Get(requestIDHeader))
+	}
+	if tagged == req {
+		t.Fatalf("expected tagRequest to return a clone, not the original request")
+	}
+}
+
+func TestRequestIDFromResponseRoundTrips(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	tagged, id := tagRequest(req)
+	resp := &http.Response{Request: tagged}
+
+	got, ok := RequestIDFromResponse(resp)
+
+	if !ok || got != id {
+		t.Fatalf("expected (%q, true), got (%q, %t)", id, got, ok)
+	}
+}
+
+func TestRequestIDFromResponseFalseForNilResponse(t *testing.T) {
+	if _, ok := RequestIDFromResponse(nil); ok {
+		t.Fatalf("expected false for a nil response")
+	}
+}
+
+func TestServerRequestIDsCollectsKnownHeaders(t *testing.T) {
+	resp := &http.Response{Header: http.Header{
+		"X-Request-Id":            []string{"abc"},
+		"X-Global-Transaction-Id": []string{"xyz"},
+		"X-Other":                 []string{"ignored"},
+	}}
+
+	ids := serverRequestIDs(resp)
+
+	if ids["X-Request-ID"] != "abc" || ids["X-Global-Transaction-Id"] != "xyz" {
+		t.Fatalf("expected known transaction id headers to be collected, got %v", ids)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected only known headers to be collected, got %v", ids)
+	}
+}
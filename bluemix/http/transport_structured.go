@@ -0,0 +1,235 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+// TraceFormat selects how TraceLoggingTransport writes trace output.
+type TraceFormat int
+
+const (
+	// TraceFormatText is the classic REQUEST/RESPONSE block format
+	// written to trace.Logger. This is the default.
+	TraceFormatText TraceFormat = iota
+
+	// TraceFormatJSON emits one TraceRecord per HTTP transaction, as a
+	// JSON line, to trace.Logger. Intended for CI systems and
+	// observability pipelines that would otherwise have to scrape the
+	// colored text format.
+	TraceFormatJSON
+
+	// TraceFormatSlog sends one slog.Record per HTTP transaction to
+	// TraceLoggingOptions.SlogHandler.
+	TraceFormatSlog
+)
+
+// TraceTiming is the httptrace timing portion of a TraceRecord, populated
+// only when TraceLoggingOptions.DumpTiming is set.
+type TraceTiming struct {
+	DNSMS       float64 `json:"dns_ms,omitempty"`
+	ConnectMS   float64 `json:"connect_ms,omitempty"`
+	TLSMS       float64 `json:"tls_ms,omitempty"`
+	GotConnMS   float64 `json:"got_conn_ms,omitempty"`
+	FirstByteMS float64 `json:"first_byte_ms,omitempty"`
+	Reused      bool    `json:"reused"`
+	WasIdle     bool    `json:"was_idle"`
+}
+
+// TraceRecord is one structured log entry describing a single HTTP
+// transaction, emitted instead of the text REQUEST/RESPONSE blocks when
+// TraceLoggingOptions.Format is TraceFormatJSON or TraceFormatSlog.
+type TraceRecord struct {
+	RequestID  string    `json:"request_id"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DurationMS float64   `json:"duration_ms"`
+
+	// Error is the RoundTrip error, if any. When set, Status, response
+	// headers/body, and ServerRequestID are all empty since no response
+	// was received.
+	Error string `json:"error,omitempty"`
+
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"request_header"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	RequestBodyLen int         `json:"request_body_len"`
+
+	Status          int         `json:"status"`
+	ResponseHeader  http.Header `json:"response_header"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+	ResponseBodyLen int         `json:"response_body_len"`
+
+	// ServerRequestID maps server-assigned transaction ID headers (see
+	// serverRequestIDHeaders) to their values, so the client-side
+	// RequestID can be cross-referenced with support tickets.
+	ServerRequestID map[string]string `json:"server_request_id,omitempty"`
+
+	Timing *TraceTiming `json:"timing,omitempty"`
+}
+
+// captureBody reads *body fully and replaces it with a fresh
+// io.ReadCloser over the same bytes, so callers downstream of
+// TraceLoggingTransport still see an unconsumed body.
+func captureBody(body *io.ReadCloser) []byte {
+	if body == nil || *body == nil {
+		return nil
+	}
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil
+	}
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data
+}
+
+// sanitizeHeader redacts sensitive header values via sanitizer before a
+// header map is included in a TraceRecord. It round-trips through the
+// wire format so the same Sanitizer rules that redact headers in the text
+// dump (e.g. Authorization) apply here too.
+func sanitizeHeader(h http.Header, sanitizer Sanitizer) http.Header {
+	var buf bytes.Buffer
+	if err := h.Write(&buf); err != nil {
+		return h.Clone()
+	}
+
+	sanitized := sanitizer.Sanitize(buf.String(), "")
+
+	reader := textproto.NewReader(bufio.NewReader(strings.NewReader(sanitized + "\r\n")))
+	parsed, err := reader.ReadMIMEHeader()
+	if err != nil {
+		return h.Clone()
+	}
+	return http.Header(parsed)
+}
+
+func msSince(ref, mark time.Time) float64 {
+	if mark.IsZero() {
+		return 0
+	}
+	return mark.Sub(ref).Seconds() * 1000
+}
+
+func traceTimingFrom(t *httpTiming) *TraceTiming {
+	if t == nil {
+		return nil
+	}
+	return &TraceTiming{
+		DNSMS:       msSince(t.start, t.dnsDone),
+		ConnectMS:   msSince(t.start, t.connectDone),
+		TLSMS:       msSince(t.start, t.tlsDone),
+		GotConnMS:   msSince(t.start, t.gotConn),
+		FirstByteMS: msSince(t.start, t.firstByte),
+		Reused:      t.reused,
+		WasIdle:     t.wasIdle,
+	}
+}
+
+// logStructured builds a TraceRecord for one HTTP transaction and emits it
+// in r.options.Format. reqBody/resBody are raw, unsanitized bytes captured
+// from the request/response; they are sanitized here before being
+// recorded. Request fields are populated only if r.options.DumpRequest is
+// set, response fields only if r.options.DumpResponse is set - mirroring
+// what those options mean in text mode. res and roundTripErr are mutually
+// exclusive: when the RoundTrip failed, res is nil and roundTripErr
+// records why, so failed transactions still produce a record instead of
+// being silently dropped.
+func (r *TraceLoggingTransport) logStructured(req *http.Request, res *http.Response, id string, start, end time.Time, reqBody, resBody []byte, timing *httpTiming, roundTripErr error) {
+	record := TraceRecord{
+		RequestID:  id,
+		Start:      start,
+		End:        end,
+		DurationMS: end.Sub(start).Seconds() * 1000,
+		Timing:     traceTimingFrom(timing),
+	}
+	if roundTripErr != nil {
+		record.Error = roundTripErr.Error()
+	}
+
+	if r.options.DumpRequest {
+		record.Method = req.Method
+		record.URL = req.URL.String()
+		record.RequestHeader = sanitizeHeader(req.Header, r.options.Sanitizer)
+		record.RequestBodyLen = len(reqBody)
+		if r.options.DumpBody && len(reqBody) > 0 {
+			record.RequestBody = r.options.Sanitizer.Sanitize(string(reqBody), req.Header.Get("Content-Type"))
+		}
+	}
+
+	if r.options.DumpResponse && res != nil {
+		record.Status = res.StatusCode
+		record.ResponseHeader = sanitizeHeader(res.Header, r.options.Sanitizer)
+		record.ResponseBodyLen = len(resBody)
+		record.ServerRequestID = serverRequestIDs(res)
+		if r.options.DumpBody && len(resBody) > 0 {
+			record.ResponseBody = r.options.Sanitizer.Sanitize(string(resBody), res.Header.Get("Content-Type"))
+		}
+	}
+
+	switch r.options.Format {
+	case TraceFormatJSON:
+		line, err := json.Marshal(record)
+		if err != nil {
+			trace.Logger.Printf("An error occurred while marshaling trace record:\n%s\n", err.Error())
+			return
+		}
+		trace.Logger.Println(string(line))
+	case TraceFormatSlog:
+		if r.options.SlogHandler == nil {
+			return
+		}
+		_ = r.options.SlogHandler.Handle(context.Background(), slogRecord(record, end))
+	}
+}
+
+// slogRecord converts record into a slog.Record at parity with the JSON
+// sink: every TraceRecord field is attached, grouped by section, including
+// headers, bodies (or their sizes), and httptrace timing sub-fields.
+func slogRecord(record TraceRecord, end time.Time) slog.Record {
+	rec := slog.NewRecord(end, slog.LevelInfo, "http trace", 0)
+	rec.AddAttrs(
+		slog.String("request_id", record.RequestID),
+		slog.Time("start", record.Start),
+		slog.Time("end", record.End),
+		slog.Float64("duration_ms", record.DurationMS),
+		slog.String("error", record.Error),
+		slog.Group("request",
+			slog.String("method", record.Method),
+			slog.String("url", record.URL),
+			slog.Any("header", record.RequestHeader),
+			slog.String("body", record.RequestBody),
+			slog.Int("body_len", record.RequestBodyLen),
+		),
+		slog.Group("response",
+			slog.Int("status", record.Status),
+			slog.Any("header", record.ResponseHeader),
+			slog.String("body", record.ResponseBody),
+			slog.Int("body_len", record.ResponseBodyLen),
+			slog.Any("server_request_id", record.ServerRequestID),
+		),
+	)
+	if record.Timing != nil {
+		rec.AddAttrs(slog.Group("timing",
+			slog.Float64("dns_ms", record.Timing.DNSMS),
+			slog.Float64("connect_ms", record.Timing.ConnectMS),
+			slog.Float64("tls_ms", record.Timing.TLSMS),
+			slog.Float64("got_conn_ms", record.Timing.GotConnMS),
+			slog.Float64("first_byte_ms", record.Timing.FirstByteMS),
+			slog.Bool("reused", record.Timing.Reused),
+			slog.Bool("was_idle", record.Timing.WasIdle),
+		))
+	}
+	return rec
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+func TestSanitizeHeaderAppliesSanitizerAndPreservesOtherHeaders(t *testing.T) {
+	h := http.Header{"Authorization": []string{"Bearer sekret"}, "X-Other": []string{"value"}}
+	redactor := sanitizerFunc(func(dump, _ string) string {
+		return strings.ReplaceAll(dump, "Bearer sekret", RedactedValue)
+	})
+
+	got := sanitizeHeader(h, redactor)
+
+	if got.Get("Authorization") != RedactedValue {
+		t.Fatalf("expected Authorization to be redacted, got %q", got.Get("Authorization"))
+	}
+	if got.Get("X-Other") != "value" {
+		t.Fatalf("expected non-sensitive header to survive, got %q", got.Get("X-Other"))
+	}
+}
+
+func TestMsSinceReturnsZeroForZeroMark(t *testing.T) {
+	if got := msSince(time.Now(), time.Time{}); got != 0 {
+		t.Fatalf("expected 0 for a mark that never fired, got %v", got)
+	}
+}
+
+func TestTraceTimingFromNilTimingReturnsNil(t *testing.T) {
+	if got := traceTimingFrom(nil); got != nil {
+		t.Fatalf("expected nil TraceTiming for nil httpTiming, got %+v", got)
+	}
+}
+
+func TestLogStructuredJSONOmitsResponseFieldsOnError(t *testing.T) {
+	opts := TraceLoggingOptions{Format: TraceFormatJSON, DumpRequest: true, DumpResponse: true, Sanitizer: DefaultSanitizer()}
+	transport := &TraceLoggingTransport{options: opts}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	var buf strings.Builder
+	prevOut := trace.Logger.Writer()
+	trace.Logger.SetOutput(&buf)
+	defer trace.Logger.SetOutput(prevOut)
+
+	start := time.Now()
+	transport.logStructured(req, nil, "req-1", start, start, nil, nil, nil, errBoom)
+
+	var record TraceRecord
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &record); err != nil {
+		t.Fatalf("expected a valid JSON record, got %q: %v", buf.String(), err)
+	}
+	if record.Error != errBoom.Error() {
+		t.Fatalf("expected record.Error to be %q, got %q", errBoom.Error(), record.Error)
+	}
+	if record.Status != 0 || record.ResponseHeader != nil {
+		t.Fatalf("expected no response fields to be populated on a failed transaction, got %+v", record)
+	}
+}
+
+func TestLogStructuredOmitsRequestFieldsWhenDumpRequestDisabled(t *testing.T) {
+	opts := TraceLoggingOptions{Format: TraceFormatJSON, DumpRequest: false, DumpResponse: false, Sanitizer: DefaultSanitizer()}
+	transport := &TraceLoggingTransport{options: opts}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	var buf strings.Builder
+	prevOut := trace.Logger.Writer()
+	trace.Logger.SetOutput(&buf)
+	defer trace.Logger.SetOutput(prevOut)
+
+	transport.logStructured(req, nil, "req-2", time.Now(), time.Now(), nil, nil, nil, nil)
+
+	var record TraceRecord
+	if err := json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &record); err != nil {
+		t.Fatalf("expected a valid JSON record, got %q: %v", buf.String(), err)
+	}
+	if record.Method != "" || record.RequestHeader != nil {
+		t.Fatalf("expected request fields to be omitted when DumpRequest is false, got %+v", record)
+	}
+}
+
+var errBoom = &boomError{}
+
+type boomError struct{}
+
+func (*boomError) Error() string { return "boom" }
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/terminal"
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+	. "github.com/IBM-Cloud/ibm-cloud-cli-sdk/i18n"
+)
+
+// httpTiming accumulates the httptrace.ClientTrace events for a single
+// RoundTrip so they can be logged as one summary once the response
+// arrives. Zero time.Time fields mean the corresponding event never
+// fired, e.g. TLSHandshake on a plain HTTP request or DNS/Connect on a
+// reused connection.
+type httpTiming struct {
+	start time.Time
+
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	gotConn                   time.Time
+	firstByte                 time.Time
+
+	reused, wasIdle       bool
+	idleTime              time.Duration
+	localAddr, remoteAddr string
+}
+
+// newHTTPTiming creates an httptrace.ClientTrace that records the timing
+// of DNS resolution, connection setup, and TLS handshake for a single HTTP
+// transaction into the returned *httpTiming.
+func newHTTPTiming(start time.Time) (*httpTiming, *httptrace.ClientTrace) {
+	t := &httpTiming{start: start}
+	return t, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.dnsDone = time.Now() },
+
+		ConnectStart: func(string, string) { t.connectStart = time.Now() },
+		ConnectDone:  func(string, string, error) { t.connectDone = time.Now() },
+
+		TLSHandshakeStart: func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.tlsDone = time.Now() },
+
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.gotConn = time.Now()
+			t.reused = info.Reused
+			t.wasIdle = info.WasIdle
+			t.idleTime = info.IdleTime
+			if info.Conn != nil {
+				t.localAddr = info.Conn.LocalAddr().String()
+				t.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+
+		GotFirstResponseByte: func() { t.firstByte = time.Now() },
+	}
+}
+
+// since returns the duration from t.start to mark formatted in
+// milliseconds, or "" if mark never fired.
+func (t *httpTiming) since(mark time.Time) string {
+	if mark.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%.0fms", mark.Sub(t.start).Seconds()*1000)
+}
+
+// log writes a compact one-line timing summary, prefixed with the
+// request's transaction id, to trace.Logger. Events that did not fire
+// (e.g. no DNS lookup on a reused connection) are omitted rather than
+// printed as zero.
+func (t *httpTiming) log(id string) {
+	var parts []string
+	if !t.dnsStart.IsZero() {
+		parts = append(parts, fmt.Sprintf("dns=%s", t.since(t.dnsDone)))
+	}
+	if !t.connectStart.IsZero() {
+		parts = append(parts, fmt.Sprintf("connect=%s", t.since(t.connectDone)))
+	}
+	if !t.tlsStart.IsZero() {
+		parts = append(parts, fmt.Sprintf("tls=%s", t.since(t.tlsDone)))
+	}
+	if !t.gotConn.IsZero() {
+		parts = append(parts, fmt.Sprintf("got_conn=%s (reused=%t was_idle=%t idle=%s local=%s remote=%s)",
+			t.since(t.gotConn), t.reused, t.wasIdle, t.idleTime, t.localAddr, t.remoteAddr))
+	}
+	if !t.firstByte.IsZero() {
+		parts = append(parts, fmt.Sprintf("first_byte=%s", t.since(t.firstByte)))
+	}
+
+	if len(parts) == 0 {
+		return
+	}
+
+	trace.Logger.Printf("\n%s [%s] %s\n", terminal.HeaderColor(T("TIMING:")), id, strings.Join(parts, " "))
+}
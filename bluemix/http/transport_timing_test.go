@@ -0,0 +1,67 @@
+package http
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM-Cloud/ibm-cloud-cli-sdk/bluemix/trace"
+)
+
+func TestHTTPTimingSinceFormatsElapsedMilliseconds(t *testing.T) {
+	start := time.Now()
+	timing := &httpTiming{start: start}
+
+	got := timing.since(start.Add(150 * time.Millisecond))
+
+	if got != "150ms" {
+		t.Fatalf("expected %q, got %q", "150ms", got)
+	}
+}
+
+func TestHTTPTimingSinceReturnsEmptyForZeroMark(t *testing.T) {
+	timing := &httpTiming{start: time.Now()}
+
+	if got := timing.since(time.Time{}); got != "" {
+		t.Fatalf("expected empty string for a mark that never fired, got %q", got)
+	}
+}
+
+func TestHTTPTimingLogOmitsEventsThatNeverFired(t *testing.T) {
+	start := time.Now()
+	timing := &httpTiming{
+		start:   start,
+		gotConn: start.Add(10 * time.Millisecond),
+	}
+
+	var buf strings.Builder
+	prevOut := trace.Logger.Writer()
+	trace.Logger.SetOutput(&buf)
+	defer trace.Logger.SetOutput(prevOut)
+
+	timing.log("req-1")
+
+	out := buf.String()
+	if !strings.Contains(out, "req-1") {
+		t.Fatalf("expected log line to include the request id, got %q", out)
+	}
+	if !strings.Contains(out, "got_conn=") {
+		t.Fatalf("expected log line to include got_conn timing, got %q", out)
+	}
+	if strings.Contains(out, "dns=") || strings.Contains(out, "tls=") {
+		t.Fatalf("expected log line to omit events that never fired, got %q", out)
+	}
+}
+
+func TestHTTPTimingLogSkipsEmptySummary(t *testing.T) {
+	var buf strings.Builder
+	prevOut := trace.Logger.Writer()
+	trace.Logger.SetOutput(&buf)
+	defer trace.Logger.SetOutput(prevOut)
+
+	(&httpTiming{start: time.Now()}).log("req-2")
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing logged when no event fired, got %q", buf.String())
+	}
+}